@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// composeFlags backs the persistent flags shared by every compose
+// subcommand.
+var composeFlags struct {
+	projectName string
+	profiles    []string
+	files       []string
+	envFile     string
+	followLogs  bool
+}
+
+func optionsFromFlags() ComposeOptions {
+	return ComposeOptions{
+		ProjectName: composeFlags.projectName,
+		Profiles:    composeFlags.profiles,
+		Files:       composeFlags.files,
+		EnvFile:     composeFlags.envFile,
+		FollowLogs:  composeFlags.followLogs,
+	}
+}
+
+// newRootCmd builds the docker-manager CLI: up/down/logs/ps/restart,
+// all driven by the Compose wrapper in compose.go.
+func newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "docker-manager",
+		Short:         "Bootstraps Docker Desktop/Engine and drives a compose project",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVarP(&composeFlags.projectName, "project-name", "p", "", "Compose project name")
+	root.PersistentFlags().StringSliceVar(&composeFlags.profiles, "profile", nil, "Compose profile(s) to enable (repeatable)")
+	root.PersistentFlags().StringSliceVarP(&composeFlags.files, "file", "f", nil, "Compose file(s) to use (repeatable)")
+	root.PersistentFlags().StringVar(&composeFlags.envFile, "env-file", "", "Env file to pass to compose")
+
+	root.AddCommand(newUpCmd(), newDownCmd(), newLogsCmd(), newPsCmd(), newRestartCmd())
+	return root
+}
+
+func newUpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Install/start Docker if needed, then bring the compose project up",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := ensureDockerReady(ctx); err != nil {
+				return err
+			}
+			compose, err := detectComposeCommand()
+			if err != nil {
+				return err
+			}
+			return compose.Up(ctx, optionsFromFlags())
+		},
+	}
+	cmd.Flags().BoolVar(&composeFlags.followLogs, "follow-logs", false, "Stream logs after startup; Ctrl-C runs compose down")
+	return cmd
+}
+
+func newDownCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "down",
+		Short: "Stop and remove the compose project",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compose, err := detectComposeCommand()
+			if err != nil {
+				return err
+			}
+			return compose.Down(cmd.Context(), optionsFromFlags())
+		},
+	}
+}
+
+func newLogsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs",
+		Short: "Stream compose service logs with colored per-service prefixes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compose, err := detectComposeCommand()
+			if err != nil {
+				return err
+			}
+			return compose.streamColoredLogs(cmd.Context(), optionsFromFlags())
+		},
+	}
+}
+
+func newPsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ps",
+		Short: "Show compose service status",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compose, err := detectComposeCommand()
+			if err != nil {
+				return err
+			}
+			statuses, err := compose.Ps(cmd.Context(), optionsFromFlags())
+			if err != nil {
+				return err
+			}
+			for _, s := range statuses {
+				fmt.Printf("%-20s %-10s %s\n", s.Service, s.State, orNone(s.Health))
+			}
+			return nil
+		},
+	}
+}
+
+func newRestartCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restart [service...]",
+		Short: "Restart one or more services, or the whole project if none are named",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			compose, err := detectComposeCommand()
+			if err != nil {
+				return err
+			}
+			return compose.Restart(cmd.Context(), optionsFromFlags(), args)
+		},
+	}
+}
+
+func main() {
+	if err := newRootCmd().ExecuteContext(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
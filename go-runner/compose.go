@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// ComposeOptions carries the project-wide flags every compose
+// subcommand accepts: which project name to operate under, which
+// profiles to enable, which compose file(s) to read, and which
+// env-file to source.
+type ComposeOptions struct {
+	ProjectName string
+	Profiles    []string
+	Files       []string
+	EnvFile     string
+	FollowLogs  bool
+}
+
+func (o ComposeOptions) globalArgs() []string {
+	var args []string
+	if o.ProjectName != "" {
+		args = append(args, "-p", o.ProjectName)
+	}
+	for _, f := range o.Files {
+		args = append(args, "-f", f)
+	}
+	if o.EnvFile != "" {
+		args = append(args, "--env-file", o.EnvFile)
+	}
+	for _, p := range o.Profiles {
+		args = append(args, "--profile", p)
+	}
+	return args
+}
+
+// Compose wraps whichever compose implementation is available on the
+// machine: the `docker compose` v2 plugin (preferred) or the legacy
+// `docker-compose` v1 binary.
+type Compose struct {
+	bin      string
+	baseArgs []string // e.g. ["compose"] for v2, nil for v1's own binary
+}
+
+// detectComposeCommand prefers `docker compose` (v2) and falls back to
+// the standalone `docker-compose` (v1) binary.
+func detectComposeCommand() (*Compose, error) {
+	if exec.Command("docker", "compose", "version").Run() == nil {
+		return &Compose{bin: "docker", baseArgs: []string{"compose"}}, nil
+	}
+	if _, err := exec.LookPath("docker-compose"); err == nil {
+		return &Compose{bin: "docker-compose"}, nil
+	}
+	return nil, fmt.Errorf("neither `docker compose` (v2) nor `docker-compose` (v1) is available")
+}
+
+func (c *Compose) command(ctx context.Context, opts ComposeOptions, args ...string) *exec.Cmd {
+	full := append(append([]string{}, c.baseArgs...), opts.globalArgs()...)
+	full = append(full, args...)
+	cmd := exec.CommandContext(ctx, c.bin, full...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+// Up brings the project up in the background, waits for every service
+// to report healthy/running, and (if requested) streams logs until the
+// user hits Ctrl-C, at which point it runs Down for a clean shutdown.
+func (c *Compose) Up(ctx context.Context, opts ComposeOptions) error {
+	if err := c.command(ctx, opts, "up", "-d").Run(); err != nil {
+		return fmt.Errorf("compose up: %w", err)
+	}
+
+	fmt.Println("Waiting for services to become healthy...")
+	if err := c.waitHealthy(ctx, opts, 2*time.Minute); err != nil {
+		return err
+	}
+
+	if !opts.FollowLogs {
+		return nil
+	}
+	return c.followWithGracefulDown(ctx, opts)
+}
+
+// Down stops and removes the project.
+func (c *Compose) Down(ctx context.Context, opts ComposeOptions) error {
+	if err := c.command(ctx, opts, "down").Run(); err != nil {
+		return fmt.Errorf("compose down: %w", err)
+	}
+	return nil
+}
+
+// Restart restarts the given services, or the whole project if none
+// are named.
+func (c *Compose) Restart(ctx context.Context, opts ComposeOptions, services []string) error {
+	args := append([]string{"restart"}, services...)
+	if err := c.command(ctx, opts, args...).Run(); err != nil {
+		return fmt.Errorf("compose restart: %w", err)
+	}
+	return nil
+}
+
+// ServiceStatus is the subset of `docker compose ps --format json`
+// fields we report on.
+type ServiceStatus struct {
+	Service string `json:"Service"`
+	State   string `json:"State"`
+	Health  string `json:"Health"`
+}
+
+// Ps returns the current status of every service in the project.
+func (c *Compose) Ps(ctx context.Context, opts ComposeOptions) ([]ServiceStatus, error) {
+	cmd := c.command(ctx, opts, "ps", "--format", "json")
+	cmd.Stdout = nil // capture instead of streaming to the terminal
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("compose ps: %w", err)
+	}
+	return parsePsOutput(out)
+}
+
+// parsePsOutput accepts both the v2 `compose ps --format json` shapes:
+// one JSON object per line, or a single JSON array.
+func parsePsOutput(out []byte) ([]ServiceStatus, error) {
+	trimmed := strings.TrimSpace(string(out))
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	if trimmed[0] == '[' {
+		var statuses []ServiceStatus
+		if err := json.Unmarshal(out, &statuses); err != nil {
+			return nil, err
+		}
+		return statuses, nil
+	}
+
+	var statuses []ServiceStatus
+	for _, line := range strings.Split(trimmed, "\n") {
+		if line == "" {
+			continue
+		}
+		var s ServiceStatus
+		if err := json.Unmarshal([]byte(line), &s); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// waitHealthy polls Ps until every service is either healthy or, for
+// services with no healthcheck, simply running.
+func (c *Compose) waitHealthy(ctx context.Context, opts ComposeOptions, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		statuses, err := c.Ps(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		allReady := len(statuses) > 0
+		for _, s := range statuses {
+			ready := s.State == "running" && (s.Health == "" || s.Health == "healthy")
+			fmt.Printf("  %-20s state=%-10s health=%s\n", s.Service, s.State, orNone(s.Health))
+			if !ready {
+				allReady = false
+			}
+		}
+		if allReady {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("services did not become healthy within %s", timeout)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// logColors cycles through terminal colors assigned per service so
+// interleaved log lines stay visually distinguishable.
+var logColors = []string{"\033[36m", "\033[33m", "\033[35m", "\033[32m", "\033[34m", "\033[31m"}
+
+// followWithGracefulDown streams `compose logs -f` with colored
+// per-service prefixes and traps Ctrl-C to run `compose down` before
+// exiting, so an interrupted `up --follow-logs` always leaves a clean
+// project behind.
+func (c *Compose) followWithGracefulDown(ctx context.Context, opts ComposeOptions) error {
+	logCtx, cancelLogs := context.WithCancel(ctx)
+	defer cancelLogs()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+
+	logsDone := make(chan error, 1)
+	go func() { logsDone <- c.streamColoredLogs(logCtx, opts) }()
+
+	select {
+	case <-sigCh:
+		fmt.Println("\nCaught interrupt, running compose down...")
+		cancelLogs()
+		<-logsDone
+		return c.Down(ctx, opts)
+	case err := <-logsDone:
+		return err
+	}
+}
+
+// streamColoredLogs runs `compose logs -f` and recolors the
+// "service_1  |" prefix docker compose already prints, assigning each
+// service a stable color for the duration of the stream.
+func (c *Compose) streamColoredLogs(ctx context.Context, opts ComposeOptions) error {
+	cmd := c.command(ctx, opts, "logs", "-f", "--no-color")
+	cmd.Stdout = nil // command() defaults to os.Stdout; StdoutPipe requires it unset
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	colorOf := map[string]string{}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+		service, rest, ok := strings.Cut(line, "|")
+		if !ok {
+			fmt.Println(line)
+			continue
+		}
+
+		color, known := colorOf[service]
+		if !known {
+			color = logColors[len(colorOf)%len(logColors)]
+			colorOf[service] = color
+		}
+		fmt.Printf("%s%s|\033[0m%s\n", color, service, rest)
+	}
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil // killed by our own cancellation, not a real failure
+	}
+	return err
+}
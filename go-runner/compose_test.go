@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePsOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []ServiceStatus
+	}{
+		{
+			name: "json array",
+			in:   `[{"Service":"web","State":"running","Health":"healthy"},{"Service":"db","State":"running","Health":""}]`,
+			want: []ServiceStatus{
+				{Service: "web", State: "running", Health: "healthy"},
+				{Service: "db", State: "running", Health: ""},
+			},
+		},
+		{
+			name: "line delimited",
+			in:   "{\"Service\":\"web\",\"State\":\"running\",\"Health\":\"healthy\"}\n{\"Service\":\"db\",\"State\":\"exited\",\"Health\":\"\"}\n",
+			want: []ServiceStatus{
+				{Service: "web", State: "running", Health: "healthy"},
+				{Service: "db", State: "exited", Health: ""},
+			},
+		},
+		{
+			name: "empty",
+			in:   "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePsOutput([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("parsePsOutput() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePsOutput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePsOutputInvalidJSON(t *testing.T) {
+	if _, err := parsePsOutput([]byte("not json")); err == nil {
+		t.Error("parsePsOutput() expected an error for invalid JSON, got nil")
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// DriverOpts holds the platform-specific defaults a DistroDriver exposes
+// so the rest of the program doesn't need to know how a given OS/distro
+// lays out its install paths or which package manager it uses.
+type DriverOpts struct {
+	Name        string // human readable platform name, e.g. "Ubuntu"
+	PackageMgr  string // "apt-get", "dnf", "pacman", "" for Windows/macOS
+	InstallPath string // where Docker Desktop/Engine is expected to live
+}
+
+// DistroDriver is implemented once per supported platform/distro and
+// replaces the runtime.GOOS switches that used to live in
+// isDockerInstalled, startDockerDesktop, downloadDockerDesktop, and
+// installDockerDesktop. This mirrors the driver pattern used by Azure's
+// docker-extension project.
+type DistroDriver interface {
+	BaseOpts() DriverOpts
+	IsInstalled() bool
+	InstallDocker() error
+	UninstallDocker() error
+	StartDocker() error
+	StopDocker() error
+}
+
+// detectDriver picks the DistroDriver for the machine we're running on.
+// On Linux it inspects /etc/os-release to tell the apt-based and
+// dnf-based families apart.
+func detectDriver() (DistroDriver, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return &windowsDriver{}, nil
+	case "darwin":
+		return &darwinDriver{}, nil
+	case "linux":
+		return detectLinuxDriver()
+	default:
+		return nil, fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+}
+
+// detectLinuxDriver reads /etc/os-release's ID and ID_LIKE fields to
+// choose between the apt, dnf, and pacman families.
+func detectLinuxDriver() (DistroDriver, error) {
+	id, idLike, err := readOSRelease("/etc/os-release")
+	if err != nil {
+		return nil, fmt.Errorf("could not detect Linux distro: %w", err)
+	}
+
+	switch {
+	case id == "ubuntu" || id == "debian" || strings.Contains(idLike, "debian"):
+		return &aptDriver{distroName: titleCase(id)}, nil
+	case id == "centos" || id == "rhel" || strings.Contains(idLike, "rhel"):
+		return &dnfDriver{distroName: titleCase(id)}, nil
+	case id == "fedora":
+		return &dnfDriver{distroName: "Fedora"}, nil
+	case id == "arch" || strings.Contains(idLike, "arch"):
+		return &archDriver{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported Linux distro: id=%q id_like=%q", id, idLike)
+	}
+}
+
+var titleCaser = cases.Title(language.Und)
+
+// titleCase title-cases a distro ID for display (e.g. "centos" ->
+// "Centos"). strings.Title is deprecated and Unicode-incorrect; this
+// uses the locale-aware x/text caser instead.
+func titleCase(s string) string {
+	return titleCaser.String(s)
+}
+
+// readOSRelease parses the ID and ID_LIKE values out of an os-release
+// formatted file (KEY=VALUE lines, values optionally quoted).
+func readOSRelease(path string) (id string, idLike string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch key {
+		case "ID":
+			id = strings.ToLower(value)
+		case "ID_LIKE":
+			idLike = strings.ToLower(value)
+		}
+	}
+	return id, idLike, scanner.Err()
+}
+
+// windowsDriver drives Docker Desktop on Windows.
+type windowsDriver struct{}
+
+func (d *windowsDriver) BaseOpts() DriverOpts {
+	return DriverOpts{
+		Name:        "Windows",
+		InstallPath: filepath.Join(os.Getenv("ProgramFiles"), "Docker", "Docker"),
+	}
+}
+
+func (d *windowsDriver) IsInstalled() bool {
+	opts := d.BaseOpts()
+	paths := []string{
+		filepath.Join(opts.InstallPath, "Docker Desktop.exe"),
+		filepath.Join(opts.InstallPath, "resources", "bin", "docker.exe"),
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return exec.Command("docker", "--version").Run() == nil
+}
+
+func (d *windowsDriver) InstallDocker() error {
+	return downloadAndInstall(dockerDesktopURL("windows"), installerPath)
+}
+
+func (d *windowsDriver) UninstallDocker() error {
+	uninstaller := filepath.Join(d.BaseOpts().InstallPath, "Docker Desktop Installer.exe")
+	return exec.Command(uninstaller, "uninstall", "--quiet").Run()
+}
+
+func (d *windowsDriver) StartDocker() error {
+	dockerPath := filepath.Join(d.BaseOpts().InstallPath, "Docker Desktop.exe")
+	return exec.Command(dockerPath).Start()
+}
+
+func (d *windowsDriver) StopDocker() error {
+	return exec.Command("taskkill", "/IM", "Docker Desktop.exe", "/F").Run()
+}
+
+// darwinDriver drives Docker Desktop on macOS.
+type darwinDriver struct{}
+
+func (d *darwinDriver) BaseOpts() DriverOpts {
+	return DriverOpts{Name: "macOS", InstallPath: "/Applications/Docker.app"}
+}
+
+func (d *darwinDriver) IsInstalled() bool {
+	if _, err := os.Stat(d.BaseOpts().InstallPath); err == nil {
+		return true
+	}
+	return exec.Command("docker", "--version").Run() == nil
+}
+
+func (d *darwinDriver) InstallDocker() error {
+	return downloadAndInstall(dockerDesktopURL("darwin"), "DockerDesktop.dmg")
+}
+
+func (d *darwinDriver) UninstallDocker() error {
+	return exec.Command("rm", "-rf", d.BaseOpts().InstallPath).Run()
+}
+
+func (d *darwinDriver) StartDocker() error {
+	return exec.Command("open", "-a", "Docker").Run()
+}
+
+func (d *darwinDriver) StopDocker() error {
+	return exec.Command("osascript", "-e", `quit app "Docker"`).Run()
+}
+
+// aptDriver drives Docker Engine on Debian/Ubuntu via apt-get.
+type aptDriver struct {
+	distroName string
+}
+
+func (d *aptDriver) BaseOpts() DriverOpts {
+	return DriverOpts{Name: d.distroName, PackageMgr: "apt-get"}
+}
+
+func (d *aptDriver) IsInstalled() bool {
+	return exec.Command("docker", "--version").Run() == nil
+}
+
+func (d *aptDriver) InstallDocker() error {
+	// Prefer the official convenience script; it handles repo setup for
+	// every supported Debian-family release in one shot.
+	cmd := exec.Command("sh", "-c", "curl -fsSL https://get.docker.com | sh")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (d *aptDriver) UninstallDocker() error {
+	cmd := exec.Command("apt-get", "remove", "-y", "docker-ce", "docker-ce-cli", "containerd.io")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (d *aptDriver) StartDocker() error {
+	return exec.Command("systemctl", "start", "docker").Run()
+}
+
+func (d *aptDriver) StopDocker() error {
+	return exec.Command("systemctl", "stop", "docker").Run()
+}
+
+// dnfDriver drives Docker Engine on the RHEL family (CentOS, Fedora)
+// via dnf, falling back to yum where dnf isn't present.
+type dnfDriver struct {
+	distroName string
+}
+
+func (d *dnfDriver) packageMgr() string {
+	if _, err := exec.LookPath("dnf"); err == nil {
+		return "dnf"
+	}
+	return "yum"
+}
+
+func (d *dnfDriver) BaseOpts() DriverOpts {
+	return DriverOpts{Name: d.distroName, PackageMgr: d.packageMgr()}
+}
+
+func (d *dnfDriver) IsInstalled() bool {
+	return exec.Command("docker", "--version").Run() == nil
+}
+
+func (d *dnfDriver) InstallDocker() error {
+	mgr := d.packageMgr()
+	cmd := exec.Command(mgr, "install", "-y", "docker-ce", "docker-ce-cli", "containerd.io")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (d *dnfDriver) UninstallDocker() error {
+	cmd := exec.Command(d.packageMgr(), "remove", "-y", "docker-ce", "docker-ce-cli", "containerd.io")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (d *dnfDriver) StartDocker() error {
+	return exec.Command("systemctl", "start", "docker").Run()
+}
+
+func (d *dnfDriver) StopDocker() error {
+	return exec.Command("systemctl", "stop", "docker").Run()
+}
+
+// archDriver drives Docker Engine on Arch Linux via pacman.
+type archDriver struct{}
+
+func (d *archDriver) BaseOpts() DriverOpts {
+	return DriverOpts{Name: "Arch Linux", PackageMgr: "pacman"}
+}
+
+func (d *archDriver) IsInstalled() bool {
+	return exec.Command("docker", "--version").Run() == nil
+}
+
+func (d *archDriver) InstallDocker() error {
+	cmd := exec.Command("pacman", "-S", "--noconfirm", "docker")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (d *archDriver) UninstallDocker() error {
+	cmd := exec.Command("pacman", "-R", "--noconfirm", "docker")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (d *archDriver) StartDocker() error {
+	return exec.Command("systemctl", "start", "docker").Run()
+}
+
+func (d *archDriver) StopDocker() error {
+	return exec.Command("systemctl", "stop", "docker").Run()
+}
+
+// dockerDesktopURL returns the Docker Desktop installer URL for the
+// platforms that ship one (Windows and macOS).
+func dockerDesktopURL(goos string) string {
+	switch goos {
+	case "windows":
+		return "https://desktop.docker.com/win/main/amd64/Docker%20Desktop%20Installer.exe"
+	case "darwin":
+		return "https://desktop.docker.com/mac/main/amd64/Docker.dmg"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadOSRelease(t *testing.T) {
+	tests := []struct {
+		name       string
+		contents   string
+		wantID     string
+		wantIDLike string
+	}{
+		{
+			name:       "ubuntu",
+			contents:   "NAME=\"Ubuntu\"\nID=ubuntu\nID_LIKE=debian\nVERSION_ID=\"22.04\"\n",
+			wantID:     "ubuntu",
+			wantIDLike: "debian",
+		},
+		{
+			name:       "fedora has no ID_LIKE",
+			contents:   "NAME=Fedora\nID=fedora\nVERSION_ID=40\n",
+			wantID:     "fedora",
+			wantIDLike: "",
+		},
+		{
+			name:       "centos-like rhel clone",
+			contents:   "ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\n",
+			wantID:     "rocky",
+			wantIDLike: "rhel centos fedora",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "os-release")
+			if err := os.WriteFile(path, []byte(tt.contents), 0o644); err != nil {
+				t.Fatalf("writing fixture: %v", err)
+			}
+
+			id, idLike, err := readOSRelease(path)
+			if err != nil {
+				t.Fatalf("readOSRelease() error = %v", err)
+			}
+			if id != tt.wantID {
+				t.Errorf("id = %q, want %q", id, tt.wantID)
+			}
+			if idLike != tt.wantIDLike {
+				t.Errorf("idLike = %q, want %q", idLike, tt.wantIDLike)
+			}
+		})
+	}
+}
+
+func TestReadOSReleaseMissingFile(t *testing.T) {
+	if _, _, err := readOSRelease("/no/such/file"); err == nil {
+		t.Error("readOSRelease() expected an error for a missing file, got nil")
+	}
+}
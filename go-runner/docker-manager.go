@@ -1,265 +1,101 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
 	"os/exec"
-	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 )
 
-const (
-	dockerDesktopURL = "https://desktop.docker.com/win/main/amd64/Docker%20Desktop%20Installer.exe"
-	installerPath    = "DockerDesktopInstaller.exe"
-)
+const installerPath = "DockerDesktopInstaller.exe"
 
-func main() {
-	fmt.Println("Docker Desktop Manager")
-	fmt.Println("======================")
+// ensureDockerReady makes sure Docker is installed and the engine is
+// answering before a compose command touches it, prompting to install
+// and/or starting it along the way.
+func ensureDockerReady(ctx context.Context) error {
+	driver, err := detectDriver()
+	if err != nil {
+		return err
+	}
 
-	// Check if Docker Desktop is installed
-	if !isDockerInstalled() {
-		fmt.Println("Docker Desktop is not installed.")
+	if !driver.IsInstalled() {
+		fmt.Printf("Docker is not installed on %s.\n", driver.BaseOpts().Name)
 		fmt.Print("Would you like to download and install it? (y/n): ")
-		
+
 		var response string
 		fmt.Scanln(&response)
-		
-		if strings.ToLower(response) == "y" {
-			if err := downloadDockerDesktop(); err != nil {
-				fmt.Printf("Error downloading Docker Desktop: %v\n", err)
-				waitForExit()
-				return
-			}
-			
-			if err := installDockerDesktop(); err != nil {
-				fmt.Printf("Error installing Docker Desktop: %v\n", err)
-				waitForExit()
-				return
-			}
-			
-			fmt.Println("Docker Desktop installed successfully!")
-			fmt.Println("Please restart this program after Docker Desktop installation completes.")
-			waitForExit()
-			return
-		} else {
-			fmt.Println("Docker Desktop is required to run this application.")
-			waitForExit()
-			return
+		if strings.ToLower(response) != "y" {
+			return fmt.Errorf("Docker is required to run this application")
 		}
-	}
 
-	fmt.Println("Docker Desktop is installed ✓")
-
-	// Check if Docker Desktop is running
-	if !isDockerRunning() {
-		fmt.Println("Docker Desktop is not running. Starting it now...")
-		
-		if err := startDockerDesktop(); err != nil {
-			fmt.Printf("Error starting Docker Desktop: %v\n", err)
-			waitForExit()
-			return
-		}
-
-		fmt.Println("Waiting for Docker Desktop to be ready...")
-		if err := waitForDocker(); err != nil {
-			fmt.Printf("Error waiting for Docker: %v\n", err)
-			waitForExit()
-			return
+		if err := driver.InstallDocker(); err != nil {
+			return fmt.Errorf("installing Docker: %w", err)
 		}
+		return fmt.Errorf("Docker installed successfully; please re-run this command after the installation completes")
 	}
 
-	fmt.Println("Docker Desktop is running ✓")
-
-	// Run docker-compose
-	fmt.Println("\nStarting docker-compose...")
-	if err := runDockerCompose(); err != nil {
-		fmt.Printf("Error running docker-compose: %v\n", err)
-		waitForExit()
-		return
-	}
-
-	fmt.Println("\n✓ Docker containers started successfully!")
-	waitForExit()
-}
+	fmt.Printf("Docker is installed ✓ (%s)\n", driver.BaseOpts().Name)
 
-// isDockerInstalled checks if Docker Desktop is installed
-func isDockerInstalled() bool {
-	var cmd *exec.Cmd
-	
-	switch runtime.GOOS {
-	case "windows":
-		// Check for docker.exe in common locations
-		paths := []string{
-			filepath.Join(os.Getenv("ProgramFiles"), "Docker", "Docker", "Docker Desktop.exe"),
-			filepath.Join(os.Getenv("ProgramFiles"), "Docker", "Docker", "resources", "bin", "docker.exe"),
-		}
-		
-		for _, path := range paths {
-			if _, err := os.Stat(path); err == nil {
-				return true
-			}
+	engine := newDockerClient()
+	if engine.Ping(ctx) != nil {
+		rebootRequired, err := ensureWSLReady()
+		if err != nil {
+			return fmt.Errorf("checking WSL2 prerequisites: %w", err)
 		}
-		
-		// Try to run docker command
-		cmd = exec.Command("docker", "--version")
-	case "darwin":
-		// macOS
-		if _, err := os.Stat("/Applications/Docker.app"); err == nil {
-			return true
+		if rebootRequired {
+			return fmt.Errorf("enabled required Windows features for WSL2; please reboot and re-run this command")
 		}
-		cmd = exec.Command("docker", "--version")
-	case "linux":
-		cmd = exec.Command("docker", "--version")
-	default:
-		return false
-	}
-
-	err := cmd.Run()
-	return err == nil
-}
 
-// isDockerRunning checks if Docker daemon is running
-func isDockerRunning() bool {
-	cmd := exec.Command("docker", "info")
-	err := cmd.Run()
-	return err == nil
-}
-
-// startDockerDesktop starts Docker Desktop
-func startDockerDesktop() error {
-	var cmd *exec.Cmd
-	
-	switch runtime.GOOS {
-	case "windows":
-		dockerPath := filepath.Join(os.Getenv("ProgramFiles"), "Docker", "Docker", "Docker Desktop.exe")
-		cmd = exec.Command(dockerPath)
-	case "darwin":
-		cmd = exec.Command("open", "-a", "Docker")
-	case "linux":
-		// On Linux, Docker usually runs as a service
-		cmd = exec.Command("systemctl", "start", "docker")
-	default:
-		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
-	}
-
-	return cmd.Start()
-}
+		fmt.Println("Docker is not running. Starting it now...")
+		if err := driver.StartDocker(); err != nil {
+			return fmt.Errorf("starting Docker: %w", err)
+		}
 
-// waitForDocker waits for Docker to be ready (up to 60 seconds)
-func waitForDocker() error {
-	maxAttempts := 60
-	for i := 0; i < maxAttempts; i++ {
-		if isDockerRunning() {
-			return nil
+		fmt.Println("Waiting for Docker to be ready...")
+		if err := engine.WaitReady(ctx, 60*time.Second); err != nil {
+			return fmt.Errorf("waiting for Docker: %w", err)
 		}
-		fmt.Print(".")
-		time.Sleep(1 * time.Second)
 	}
-	return fmt.Errorf("Docker did not start within 60 seconds")
-}
 
-// downloadDockerDesktop downloads the Docker Desktop installer
-func downloadDockerDesktop() error {
-	if runtime.GOOS != "windows" {
-		return fmt.Errorf("automatic download is only supported on Windows. Please download Docker Desktop manually from https://www.docker.com/products/docker-desktop")
+	if v, err := engine.Version(ctx); err == nil {
+		fmt.Printf("Docker Engine %s (API %s) is running ✓\n", v.Version, v.APIVersion)
 	}
+	return nil
+}
 
-	fmt.Println("Downloading Docker Desktop installer...")
-	
-	resp, err := http.Get(dockerDesktopURL)
-	if err != nil {
-		return err
+// downloadAndInstall downloads the installer at url to destPath and runs
+// it. On Windows the download is a Docker Desktop Installer.exe invoked
+// with --quiet; on macOS it's a .dmg that gets mounted and copied.
+func downloadAndInstall(url, destPath string) error {
+	if url == "" {
+		return fmt.Errorf("no installer available for this platform; please install Docker manually from https://www.docker.com/products/docker-desktop")
 	}
-	defer resp.Body.Close()
 
-	out, err := os.Create(installerPath)
-	if err != nil {
+	if err := verifiedDownload(url, destPath); err != nil {
 		return err
 	}
-	defer out.Close()
-
-	// Create a progress indicator
-	done := make(chan bool)
-	go func() {
-		for {
-			select {
-			case <-done:
-				return
-			default:
-				fmt.Print(".")
-				time.Sleep(500 * time.Millisecond)
-			}
-		}
-	}()
 
-	_, err = io.Copy(out, resp.Body)
-	done <- true
-	fmt.Println("\nDownload complete!")
-	
-	return err
+	fmt.Println("Running installer...")
+	return runInstaller(destPath)
 }
 
-// installDockerDesktop runs the Docker Desktop installer
-func installDockerDesktop() error {
-	if runtime.GOOS != "windows" {
-		return fmt.Errorf("automatic installation is only supported on Windows")
-	}
-
-	fmt.Println("Running Docker Desktop installer...")
-	fmt.Println("Please follow the installation wizard...")
-	
-	cmd := exec.Command(installerPath, "install", "--quiet")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	
-	if err := cmd.Run(); err != nil {
-		// Try running without quiet flag if it fails
-		cmd = exec.Command(installerPath)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		return cmd.Run()
+// runInstaller invokes the downloaded installer for the current
+// platform: a mounted .dmg on macOS, or the full elevation/exit-code
+// orchestration in install_windows.go on Windows.
+func runInstaller(path string) error {
+	if strings.HasSuffix(path, ".dmg") {
+		return runMacInstaller(path)
 	}
-	
-	return nil
+	return runWindowsInstaller(path)
 }
 
-// runDockerCompose runs docker-compose up
-func runDockerCompose() error {
-	// Get the directory where the executable is located
-	exePath, err := os.Executable()
-	if err != nil {
-		return err
-	}
-	exeDir := filepath.Dir(exePath)
-
-	// Check if docker-compose.yml exists in the same directory
-	composePath := filepath.Join(exeDir, "docker-compose.yml")
-	if _, err := os.Stat(composePath); os.IsNotExist(err) {
-		// Try docker-compose.yaml
-		composePath = filepath.Join(exeDir, "docker-compose.yaml")
-		if _, err := os.Stat(composePath); os.IsNotExist(err) {
-			return fmt.Errorf("docker-compose.yml or docker-compose.yaml not found in %s", exeDir)
-		}
+// runMacInstaller mounts the Docker.dmg, copies Docker.app into
+// /Applications, and unmounts it again.
+func runMacInstaller(path string) error {
+	if err := exec.Command("hdiutil", "attach", path).Run(); err != nil {
+		return fmt.Errorf("mounting %s: %w", path, err)
 	}
-
-	fmt.Printf("Using docker-compose file: %s\n", composePath)
-
-	// Run docker-compose up -d
-	cmd := exec.Command("docker-compose", "up", "-d")
-	cmd.Dir = exeDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	return cmd.Run()
-}
-
-// waitForExit waits for user input before exiting
-func waitForExit() {
-	fmt.Println("\nPress Enter to exit...")
-	fmt.Scanln()
+	defer exec.Command("hdiutil", "detach", "/Volumes/Docker").Run()
+	return exec.Command("cp", "-R", "/Volumes/Docker/Docker.app", "/Applications").Run()
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DockerClient talks directly to the Docker Engine API over its local
+// socket (a Unix socket on macOS/Linux, a named pipe on Windows), so
+// health checks no longer depend on the docker CLI being on PATH.
+type DockerClient struct {
+	http *http.Client
+}
+
+// newDockerClient builds a DockerClient wired to the platform's default
+// Engine API socket, via the dialEngine implementation for the current
+// OS (dockerclient_unix.go / dockerclient_windows.go).
+func newDockerClient() *DockerClient {
+	return &DockerClient{
+		http: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialEngine(ctx)
+				},
+			},
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// VersionInfo is the subset of Docker's /version response we care about.
+type VersionInfo struct {
+	Version    string `json:"Version"`
+	APIVersion string `json:"ApiVersion"`
+	Os         string `json:"Os"`
+	Arch       string `json:"Arch"`
+}
+
+// Ping hits /_ping and reports whether the engine answered successfully.
+func (c *DockerClient) Ping(ctx context.Context) error {
+	return c.get(ctx, "/_ping", nil)
+}
+
+// Version hits /version and returns the engine's reported version info.
+func (c *DockerClient) Version(ctx context.Context) (VersionInfo, error) {
+	var v VersionInfo
+	err := c.get(ctx, "/version", &v)
+	return v, err
+}
+
+func (c *DockerClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://docker"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("engine unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("engine returned %s: %s", resp.Status, body)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// WaitReady polls the engine with exponential backoff until Ping
+// succeeds or timeout elapses, reporting which subsystem looks like
+// it's holding things up (engine socket not up yet vs. engine up but
+// not answering, which usually means the WSL2/VM backend or buildkit is
+// still initializing).
+func (c *DockerClient) WaitReady(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := 250 * time.Millisecond
+	const maxBackoff = 5 * time.Second
+
+	var lastErr error
+	for {
+		pingCtx, pingCancel := context.WithTimeout(ctx, 2*time.Second)
+		err := c.Ping(pingCtx)
+		pingCancel()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("docker engine not ready after %s (%s): %w", timeout, diagnose(lastErr), ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// diagnose turns a low-level connection error into an actionable hint
+// about which subsystem is likely not ready yet.
+func diagnose(err error) string {
+	if err == nil {
+		return "unknown"
+	}
+	msg := err.Error()
+	switch {
+	case containsAny(msg, "no such file", "cannot find the file", "connect: connection refused"):
+		return "engine has not started listening yet"
+	case containsAny(msg, "timeout", "deadline exceeded"):
+		return "engine is listening but not responding; WSL2 backend or buildkit may still be starting"
+	default:
+		return msg
+	}
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
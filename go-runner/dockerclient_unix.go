@@ -0,0 +1,18 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"net"
+)
+
+// engineSocket is the default Docker Engine API socket on macOS and
+// Linux.
+const engineSocket = "/var/run/docker.sock"
+
+// dialEngine connects to the local Engine API over its Unix socket.
+func dialEngine(ctx context.Context) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", engineSocket)
+}
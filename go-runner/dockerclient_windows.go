@@ -0,0 +1,20 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// engineSocket is the default Docker Engine API named pipe on Windows.
+const engineSocket = `\\.\pipe\docker_engine`
+
+// dialEngine connects to the local Engine API over its named pipe.
+// net.Dial has no built-in named-pipe support, so this goes through
+// go-winio the same way Docker's own CLI and SDK do.
+func dialEngine(ctx context.Context) (net.Conn, error) {
+	return winio.DialPipeContext(ctx, engineSocket)
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// knownChecksums pins the expected SHA-256 digest for installers whose
+// release we've shipped a checksum for. Downloads whose URL isn't
+// listed here fall back to fetching "<url>.sha256" alongside the
+// installer, same as Docker's own release process publishes.
+var knownChecksums = map[string]string{}
+
+// verifiedDownload fetches url into destPath, resuming a partial
+// download via HTTP Range if destPath already exists, rendering a live
+// percentage/ETA progress bar, and verifying the result against a
+// SHA-256 checksum before returning. On Windows it also checks the
+// installer's Authenticode signature.
+func verifiedDownload(url, destPath string) error {
+	total, err := contentLength(url)
+	if err != nil {
+		return fmt.Errorf("checking download size: %w", err)
+	}
+
+	resumeFrom := int64(0)
+	if info, err := os.Stat(destPath); err == nil {
+		resumeFrom = info.Size()
+	}
+	if resumeFrom >= total && total > 0 {
+		resumeFrom = 0 // stale/complete leftover, start clean
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if resumeFrom > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", resumeFrom))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("downloading %s: unexpected status %s", url, resp.Status)
+	}
+
+	resuming := resumeFrom > 0 && resp.StatusCode == http.StatusPartialContent
+	flags := os.O_CREATE | os.O_WRONLY
+	if resuming {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+		resumeFrom = 0
+	}
+
+	out, err := os.OpenFile(destPath, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	hasher := sha256.New()
+	if resuming {
+		if err := hashExistingPrefix(destPath, resumeFrom, hasher); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Downloading %s...\n", destPath)
+	progress := newProgressReader(resp.Body, total, resumeFrom)
+	if _, err := io.Copy(out, io.TeeReader(progress, hasher)); err != nil {
+		return err
+	}
+	progress.finish()
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	expected, err := expectedChecksum(url)
+	if err != nil {
+		return fmt.Errorf("fetching expected checksum: %w", err)
+	}
+	if expected == "" {
+		fmt.Printf("warning: no checksum available for %s, skipping integrity check\n", url)
+	} else if sum != expected {
+		os.Remove(destPath)
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", destPath, sum, expected)
+	}
+
+	if runtime.GOOS == "windows" && strings.HasSuffix(destPath, ".exe") {
+		if err := verifyAuthenticode(destPath); err != nil {
+			os.Remove(destPath)
+			return fmt.Errorf("signature verification failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// contentLength issues a HEAD request to learn the download size ahead
+// of time, so the progress bar can report a percentage and ETA.
+func contentLength(url string) (int64, error) {
+	resp, err := http.Head(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("checking %s: unexpected status %s", url, resp.Status)
+	}
+	return resp.ContentLength, nil
+}
+
+// hashExistingPrefix re-hashes the bytes already on disk so the final
+// digest covers the whole file, not just the resumed tail.
+func hashExistingPrefix(path string, n int64, hasher io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.CopyN(hasher, f, n)
+	return err
+}
+
+// expectedChecksum returns the pinned checksum for url, or fetches
+// "<url>.sha256" if none is pinned. The checksum file is expected to
+// contain the hex digest as its first whitespace-separated field.
+//
+// Docker doesn't publish a ".sha256" sidecar next to every installer it
+// hosts, so a missing sidecar (404) is not treated as an error: it
+// returns ("", nil) and the caller skips verification for that
+// download rather than failing closed on URLs we have no way to pin
+// today. A network failure while checking, on the other hand, is
+// reported so a flaky connection doesn't silently disable verification.
+func expectedChecksum(url string) (string, error) {
+	if sum, ok := knownChecksums[url]; ok {
+		return sum, nil
+	}
+
+	resp, err := http.Get(url + ".sha256")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("no pinned checksum for %s and %s.sha256 returned %s", url, url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum file for %s", url)
+	}
+	return fields[0], nil
+}
+
+// verifyAuthenticode shells out to signtool to confirm the downloaded
+// installer carries a valid, trusted Microsoft/Docker signature before
+// we execute it.
+func verifyAuthenticode(path string) error {
+	cmd := exec.Command("signtool", "verify", "/pa", path)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// progressReader wraps an io.Reader, rendering a live percentage/ETA
+// progress bar as bytes flow through it. Throughput is smoothed over a
+// trailing 5-second window rather than computed over the whole
+// transfer, so the ETA reacts to recent network conditions.
+type progressReader struct {
+	r         io.Reader
+	total     int64
+	read      int64
+	lastPrint time.Time
+	samples   []progressSample
+}
+
+type progressSample struct {
+	at    time.Time
+	bytes int64
+}
+
+func newProgressReader(r io.Reader, total, alreadyRead int64) *progressReader {
+	return &progressReader{r: r, total: total, read: alreadyRead}
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	p.read += int64(n)
+	p.recordSample()
+	if time.Since(p.lastPrint) > 200*time.Millisecond {
+		p.print()
+		p.lastPrint = time.Now()
+	}
+	return n, err
+}
+
+func (p *progressReader) recordSample() {
+	now := time.Now()
+	p.samples = append(p.samples, progressSample{at: now, bytes: p.read})
+	cutoff := now.Add(-5 * time.Second)
+	i := 0
+	for i < len(p.samples) && p.samples[i].at.Before(cutoff) {
+		i++
+	}
+	p.samples = p.samples[i:]
+}
+
+func (p *progressReader) throughput() float64 {
+	if len(p.samples) < 2 {
+		return 0
+	}
+	first, last := p.samples[0], p.samples[len(p.samples)-1]
+	dt := last.at.Sub(first.at).Seconds()
+	if dt <= 0 {
+		return 0
+	}
+	return float64(last.bytes-first.bytes) / dt
+}
+
+func (p *progressReader) print() {
+	pct := 0.0
+	if p.total > 0 {
+		pct = float64(p.read) / float64(p.total) * 100
+	}
+	speed := p.throughput()
+	eta := "?"
+	if speed > 0 && p.total > 0 {
+		remaining := time.Duration(float64(p.total-p.read) / speed * float64(time.Second))
+		eta = remaining.Round(time.Second).String()
+	}
+	fmt.Printf("\r  %6.2f%%  %s/s  ETA %-8s", pct, humanBytes(speed), eta)
+}
+
+func (p *progressReader) finish() {
+	p.print()
+	fmt.Println()
+}
+
+func humanBytes(n float64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f%s", n, units[i])
+}
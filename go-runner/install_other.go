@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import "fmt"
+
+// runWindowsInstaller only makes sense on Windows; non-Windows drivers
+// install via runMacInstaller or their package manager instead.
+func runWindowsInstaller(path string) error {
+	return fmt.Errorf("runWindowsInstaller called on non-Windows platform")
+}
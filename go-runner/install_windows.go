@@ -0,0 +1,136 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+const (
+	exitCodeSuccess        = 0
+	exitCodeRebootRequired = 3010
+	exitCodeUserCancelled  = 1602
+)
+
+// runWindowsInstaller relaunches the current process elevated if it
+// isn't already Administrator, runs the Docker Desktop installer with
+// its documented silent-install flags, interprets the installer's exit
+// code, and verifies com.docker.service is running afterward.
+func runWindowsInstaller(path string) error {
+	if !isElevated() {
+		fmt.Println("Administrator privileges are required; relaunching elevated...")
+		if err := relaunchElevated(); err != nil {
+			return fmt.Errorf("relaunching elevated: %w", err)
+		}
+		return fmt.Errorf("relaunched elevated in a new window; re-run this command from there")
+	}
+
+	cmd := exec.Command(path,
+		"install",
+		"--accept-license",
+		"--backend=wsl-2",
+		"--always-run-service",
+		"--no-windows-containers",
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+
+	switch code := exitCodeOf(runErr); code {
+	case exitCodeSuccess:
+		// fall through to service verification below
+	case exitCodeRebootRequired:
+		if err := scheduleRunOnceContinuation(); err != nil {
+			return fmt.Errorf("scheduling post-reboot continuation: %w", err)
+		}
+		return fmt.Errorf("installer requires a reboot; it will resume automatically after restart")
+	case exitCodeUserCancelled:
+		return fmt.Errorf("installation was cancelled")
+	default:
+		return fmt.Errorf("installer exited with unexpected code %d", code)
+	}
+
+	return verifyDockerService()
+}
+
+// exitCodeOf extracts the process exit code from the error returned by
+// exec.Cmd.Run, treating a nil error as success.
+func exitCodeOf(err error) int {
+	if err == nil {
+		return exitCodeSuccess
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// isElevated reports whether the current process token has
+// Administrator privileges.
+func isElevated() bool {
+	token := windows.GetCurrentProcessToken()
+	return token.IsElevated()
+}
+
+// relaunchElevated re-invokes the current executable with the same
+// arguments via a UAC "runas" prompt.
+func relaunchElevated() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	verb, _ := syscall.UTF16PtrFromString("runas")
+	file, _ := syscall.UTF16PtrFromString(exe)
+	args, _ := syscall.UTF16PtrFromString(strings.Join(os.Args[1:], " "))
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	dir, _ := syscall.UTF16PtrFromString(cwd)
+
+	return windows.ShellExecute(0, verb, file, args, dir, windows.SW_SHOWNORMAL)
+}
+
+// scheduleRunOnceContinuation registers the current executable under
+// HKCU\...\RunOnce so a reboot required by the installer (exit code
+// 3010) automatically resumes this program afterward.
+func scheduleRunOnceContinuation() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\RunOnce`, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	cmd := exe + " " + strings.Join(os.Args[1:], " ")
+	return key.SetStringValue("DockerManagerContinue", cmd)
+}
+
+// verifyDockerService confirms com.docker.service is running after
+// install and starts it if it's merely stopped.
+func verifyDockerService() error {
+	out, err := exec.Command("sc", "query", "com.docker.service").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("querying com.docker.service: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if strings.Contains(string(out), "RUNNING") {
+		return nil
+	}
+
+	fmt.Println("com.docker.service is installed but not running; starting it...")
+	return exec.Command("sc", "start", "com.docker.service").Run()
+}
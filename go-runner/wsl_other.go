@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+// ensureWSLReady is a no-op outside Windows; WSL2 only exists there.
+func ensureWSLReady() (rebootRequired bool, err error) {
+	return false, nil
+}
@@ -0,0 +1,160 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+// featureState is the result of querying a Windows optional feature via
+// dism.exe. featureUnknown means the query itself failed (most commonly
+// because dism's /Get-FeatureInfo requires an elevated token) — it is
+// NOT the same as featureDisabled and must not trigger remediation.
+type featureState int
+
+const (
+	featureUnknown featureState = iota
+	featureDisabled
+	featureEnabled
+)
+
+// wslStatus is the subset of `wsl --status` we act on.
+type wslStatus struct {
+	DefaultVersion   string
+	VMPlatform       featureState
+	SubsystemFeature featureState
+}
+
+const wslKernelUpdateURL = "https://wslstorestorage.blob.core.windows.net/wslblob/wsl_update_x64.msi"
+
+// ensureWSLReady checks that WSL2 and its prerequisite Windows features
+// are in place before we try to start Docker Desktop, since a
+// missing/outdated WSL2 kernel or a disabled Virtual Machine Platform
+// feature is Docker Desktop's most common silent-startup failure on
+// Windows. It returns rebootRequired=true if it just enabled a Windows
+// feature that needs a restart to take effect.
+func ensureWSLReady() (rebootRequired bool, err error) {
+	status, err := wslCheckStatus()
+	if err != nil {
+		return false, fmt.Errorf("checking WSL status: %w", err)
+	}
+
+	if status.VMPlatform == featureDisabled || status.SubsystemFeature == featureDisabled {
+		fmt.Println("Required Windows features for WSL2 are disabled.")
+		fmt.Println("Enabling Virtual Machine Platform and Windows Subsystem for Linux...")
+		if err := wslEnableFeatures(); err != nil {
+			return false, fmt.Errorf("enabling WSL2 Windows features: %w", err)
+		}
+		return true, nil
+	}
+
+	if status.VMPlatform == featureUnknown || status.SubsystemFeature == featureUnknown {
+		// Couldn't confirm feature state (e.g. dism needs elevation we
+		// don't have). Don't force an enable+reboot cycle on what may
+		// well be a healthy machine; just let StartDocker/WaitReady
+		// find out.
+		fmt.Println("warning: could not confirm WSL2 Windows feature state; skipping feature remediation")
+		return false, nil
+	}
+
+	if status.DefaultVersion != "2" {
+		fmt.Println("Installing the WSL2 kernel update...")
+		if err := wslInstallKernelUpdate(); err != nil {
+			return false, fmt.Errorf("installing WSL2 kernel update: %w", err)
+		}
+		if err := wslSetDefaultVersion2(); err != nil {
+			return false, fmt.Errorf("setting WSL default version to 2: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// wslCheckStatus runs `wsl --status` and parses the default version and
+// feature flags out of its (UTF-16LE, localized-but-English-by-default)
+// output.
+func wslCheckStatus() (wslStatus, error) {
+	raw, err := exec.Command("wsl", "--status").CombinedOutput()
+	out, decodeErr := decodeUTF16LE(raw)
+	if decodeErr != nil {
+		out = string(raw) // best effort: fall back to the raw bytes
+	}
+	if err != nil {
+		return wslStatus{}, fmt.Errorf("%w: %s", err, strings.TrimSpace(out))
+	}
+
+	status := wslStatus{DefaultVersion: "1"}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.Contains(line, "Default Version"):
+			if idx := strings.LastIndex(line, ":"); idx >= 0 {
+				status.DefaultVersion = strings.TrimSpace(line[idx+1:])
+			}
+		}
+	}
+
+	status.VMPlatform = dismFeatureState("VirtualMachinePlatform")
+	status.SubsystemFeature = dismFeatureState("Microsoft-Windows-Subsystem-Linux")
+
+	return status, nil
+}
+
+// decodeUTF16LE decodes wsl.exe's UTF-16LE console output (with or
+// without a BOM) into a UTF-8 string so the usual strings.Contains-style
+// parsing below actually matches.
+func decodeUTF16LE(b []byte) (string, error) {
+	decoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder().Bytes(b)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// dismFeatureState shells out to dism.exe to check whether a given
+// optional Windows feature is enabled. A query failure (commonly
+// because this process isn't elevated) reports featureUnknown rather
+// than being conflated with the feature actually being disabled.
+func dismFeatureState(feature string) featureState {
+	out, err := exec.Command("dism.exe", "/online", "/Get-FeatureInfo", "/FeatureName:"+feature).CombinedOutput()
+	if err != nil {
+		return featureUnknown
+	}
+	if strings.Contains(string(out), "State : Enabled") {
+		return featureEnabled
+	}
+	return featureDisabled
+}
+
+// wslEnableFeatures enables Virtual Machine Platform and WSL via
+// dism.exe. Both require an elevated prompt and a reboot to take
+// effect, so callers must re-run after the restart.
+func wslEnableFeatures() error {
+	for _, feature := range []string{"VirtualMachinePlatform", "Microsoft-Windows-Subsystem-Linux"} {
+		cmd := exec.Command("dism.exe", "/online", "/Enable-Feature", "/FeatureName:"+feature, "/All", "/NoRestart")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("enabling %s: %w: %s", feature, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// wslInstallKernelUpdate downloads and silently installs the standalone
+// WSL2 kernel update MSI.
+func wslInstallKernelUpdate() error {
+	const msiPath = "wsl_update_x64.msi"
+	if err := verifiedDownload(wslKernelUpdateURL, msiPath); err != nil {
+		return err
+	}
+	return exec.Command("msiexec.exe", "/i", msiPath, "/quiet", "/norestart").Run()
+}
+
+// wslSetDefaultVersion2 makes WSL2 the default for newly installed
+// distros.
+func wslSetDefaultVersion2() error {
+	return exec.Command("wsl", "--set-default-version", "2").Run()
+}